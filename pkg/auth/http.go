@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetricsCheckRequestVersionHTTP is the CheckRequestVersionLabel value
+// recorded for requests handled by RegisterHTTPServer, alongside the
+// existing gRPC v2/v3 values.
+const MetricsCheckRequestVersionHTTP = "http"
+
+// maxHTTPBodyBytes caps the body Cerberus will read from an HTTP ext_authz
+// request. Unlike the gRPC path, where Envoy frames the CheckRequest message
+// itself, this handler reads straight off the network, so it needs its own
+// bound.
+const maxHTTPBodyBytes = 1 << 20 // 1MiB
+
+// envoyAuthHeadersToRemove is the response header Envoy's HTTP ext_authz
+// client looks for on a 200 response: a comma-separated list of header
+// names to strip from the original request before it reaches the upstream.
+// It mirrors the `headers_to_remove` field of the gRPC OkHttpResponse that
+// AsV2/AsV3 already map.
+const envoyAuthHeadersToRemove = "x-envoy-auth-headers-to-remove"
+
+// authHTTP adapts a Checker to Envoy's HTTP ext_authz service, for
+// deployments where only the HTTP (not gRPC) ext_authz variant is
+// available.
+type authHTTP struct {
+	Checker Checker
+}
+
+func (a *authHTTP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reqStartTime := time.Now()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxHTTPBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	request := Request{
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Host:    r.Host,
+		Headers: r.Header,
+		Body:    body,
+	}
+
+	ctx, span := startCheckSpan(r.Context(), &request, MetricsCheckRequestVersionHTTP)
+
+	response, err := a.Checker.Check(ctx, &request)
+	finishCheckSpan(span, response, &request, err)
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	reason := CerberusReason(response.Response.Header.Get("X-Cerberus-Reason"))
+	labels := AddReasonLabel(nil, reason)
+	labels = AddUpstreamAuthLabel(labels, request.Context[HasUpstreamAuth])
+	labels = AddJWTLabels(labels, &request)
+	labels[CheckRequestVersionLabel] = MetricsCheckRequestVersionHTTP
+	reqCount.With(labels).Inc()
+	reqLatency.With(labels).Observe(time.Since(reqStartTime).Seconds())
+
+	for k, values := range response.Response.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+
+	if len(response.HeadersToRemove) > 0 {
+		w.Header().Set(envoyAuthHeadersToRemove, strings.Join(response.HeadersToRemove, ","))
+	}
+
+	status := response.Response.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	w.WriteHeader(status)
+}
+
+// RegisterHTTPServer registers c with mux as Envoy's HTTP ext_authz service,
+// the HTTP counterpart to RegisterServer's gRPC v2/v3 services.
+func RegisterHTTPServer(mux *http.ServeMux, c Checker) {
+	mux.Handle("/", &authHTTP{Checker: c})
+}
+
+// RunHTTPServer runs srv on listener until signaled by ctx, mirroring
+// RunServer's lifecycle for the gRPC server.
+func RunHTTPServer(ctx context.Context, listener net.Listener, srv *http.Server) error {
+	errChan := make(chan error)
+
+	go func() {
+		errChan <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		return srv.Shutdown(context.Background())
+	}
+}
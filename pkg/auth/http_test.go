@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthHTTPServeHTTPInjectsAndRemovesHeaders(t *testing.T) {
+	checker := &fakeChecker{response: &Response{
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"X-User": []string{"alice"}},
+		},
+		HeadersToRemove: []string{"Authorization"},
+	}}
+
+	handler := &authHTTP{Checker: checker}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body"))
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if got := rec.Header().Get("X-User"); got != "alice" {
+		t.Errorf("X-User header = %q, want alice", got)
+	}
+
+	if got := rec.Header().Get(envoyAuthHeadersToRemove); got != "Authorization" {
+		t.Errorf("%s header = %q, want Authorization", envoyAuthHeadersToRemove, got)
+	}
+}
+
+func TestAuthHTTPServeHTTPDefaultsZeroStatusToOK(t *testing.T) {
+	checker := &fakeChecker{response: &Response{Response: &http.Response{Header: http.Header{}}}}
+	handler := &authHTTP{Checker: checker}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestAuthHTTPServeHTTPPropagatesDenyStatus(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Cerberus-Reason", "IdentityDenied")
+	checker := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusForbidden, Header: header}}}
+	handler := &authHTTP{Checker: checker}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	if got := rec.Header().Get("X-Cerberus-Reason"); got != "IdentityDenied" {
+		t.Errorf("X-Cerberus-Reason = %q, want IdentityDenied", got)
+	}
+}
+
+func TestAuthHTTPServeHTTPRejectsOversizedBody(t *testing.T) {
+	checker := &fakeChecker{response: &Response{Response: &http.Response{Header: http.Header{}}}}
+	handler := &authHTTP{Checker: checker}
+
+	oversized := strings.NewReader(strings.Repeat("a", maxHTTPBodyBytes+1))
+	req := httptest.NewRequest(http.MethodPost, "/", oversized)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	if checker.called {
+		t.Error("expected Checker not to be called when the body exceeds the cap")
+	}
+}
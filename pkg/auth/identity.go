@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Context keys populated by IdentityChecker from the verified mTLS client
+// certificate, for use by Checker implementations further down the chain
+// (e.g. RateLimitChecker, or a Rego policy evaluated over Request.Context).
+const (
+	ClientSPIFFEURI  = "client_spiffe_uri"
+	ClientCommonName = "client_common_name"
+	ClientDNSSANs    = "client_dns_sans"
+)
+
+// Identity is the workload identity presented on the verified client
+// certificate chain of an incoming connection.
+type Identity struct {
+	SPIFFEURI  string
+	CommonName string
+	DNSSANs    []string
+}
+
+// IdentityFromContext extracts the verified client certificate identity from
+// ctx. It returns false when the connection did not present TLS peer info or
+// no client certificate was verified, which is the normal case unless the
+// server was built with NewServerCredentials and a CA path.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return Identity{}, false
+	}
+
+	cert := info.State.VerifiedChains[0][0]
+
+	id := Identity{
+		CommonName: cert.Subject.CommonName,
+		DNSSANs:    cert.DNSNames,
+	}
+
+	for _, uri := range cert.URIs {
+		if uri.Scheme == "spiffe" {
+			id.SPIFFEURI = uri.String()
+			break
+		}
+	}
+
+	return id, true
+}
+
+// IdentityRule matches a client Identity by glob pattern against its SPIFFE
+// URI and/or common name, and states whether a match is allowed or denied.
+// An empty pattern is not matched against.
+type IdentityRule struct {
+	SPIFFEURIPattern  string
+	CommonNamePattern string
+	Allow             bool
+}
+
+// Matches reports whether id satisfies every non-empty pattern on the rule.
+func (r IdentityRule) Matches(id Identity) bool {
+	if r.SPIFFEURIPattern == "" && r.CommonNamePattern == "" {
+		return false
+	}
+
+	if r.SPIFFEURIPattern != "" && !globMatch(r.SPIFFEURIPattern, id.SPIFFEURI) {
+		return false
+	}
+
+	if r.CommonNamePattern != "" && !globMatch(r.CommonNamePattern, id.CommonName) {
+		return false
+	}
+
+	return true
+}
+
+func globMatch(pattern, value string) bool {
+	if pattern == value {
+		return true
+	}
+
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
+}
+
+// IdentityChecker is a Checker that authorizes requests based on the
+// workload identity extracted from the mTLS client certificate, evaluating
+// Rules in order and falling through to Next when none match. It is the
+// identity-based analogue of an AccessToken rule: instead of matching a
+// bearer token, it matches the verified SPIFFE URI or CN of the caller.
+type IdentityChecker struct {
+	Rules []IdentityRule
+	Next  Checker
+}
+
+// NewIdentityChecker returns an IdentityChecker evaluating rules in order
+// before delegating unmatched requests to next.
+func NewIdentityChecker(rules []IdentityRule, next Checker) *IdentityChecker {
+	return &IdentityChecker{Rules: rules, Next: next}
+}
+
+// Check implements Checker.
+func (c *IdentityChecker) Check(ctx context.Context, request *Request) (*Response, error) {
+	id, ok := IdentityFromContext(ctx)
+	if ok {
+		if request.Context == nil {
+			request.Context = map[string]interface{}{}
+		}
+
+		request.Context[ClientSPIFFEURI] = id.SPIFFEURI
+		request.Context[ClientCommonName] = id.CommonName
+		request.Context[ClientDNSSANs] = id.DNSSANs
+
+		for _, rule := range c.Rules {
+			if !rule.Matches(id) {
+				continue
+			}
+
+			if rule.Allow {
+				break
+			}
+
+			header := http.Header{}
+			header.Set("X-Cerberus-Reason", "IdentityDenied")
+
+			return &Response{Response: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     header,
+			}}, nil
+		}
+	}
+
+	return c.Next.Check(ctx, request)
+}
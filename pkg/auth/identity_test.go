@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// fakeChecker is a Checker stub shared across this package's tests, wiring
+// decorators (IdentityChecker, JWTChecker, RateLimitChecker, ...) in front
+// of a canned response so each decorator's own Check logic can be exercised
+// in isolation.
+type fakeChecker struct {
+	called   bool
+	response *Response
+	err      error
+}
+
+func (f *fakeChecker) Check(ctx context.Context, request *Request) (*Response, error) {
+	f.called = true
+	return f.response, f.err
+}
+
+// generateTestCert builds a self-signed certificate with the given CN and,
+// optionally, a SPIFFE URI SAN, for faking a verified mTLS peer in tests.
+func generateTestCert(t *testing.T, commonName, spiffeURI string) *x509.Certificate {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+	}
+
+	if spiffeURI != "" {
+		u, err := url.Parse(spiffeURI)
+		if err != nil {
+			t.Fatalf("parsing SPIFFE URI: %v", err)
+		}
+
+		template.URIs = []*url.URL{u}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parsing certificate: %v", err)
+	}
+
+	return cert
+}
+
+// contextWithIdentity returns a context carrying a gRPC peer whose verified
+// certificate chain is just cert, as IdentityFromContext expects.
+func contextWithIdentity(cert *x509.Certificate) context.Context {
+	info := credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: info})
+}
+
+func TestGlobMatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"exact match", "spiffe://cluster/ns/payments/sa", "spiffe://cluster/ns/payments/sa", true},
+		{"glob match", "spiffe://cluster/ns/payments/*", "spiffe://cluster/ns/payments/api", true},
+		{"glob mismatch", "spiffe://cluster/ns/payments/*", "spiffe://cluster/ns/billing/api", false},
+		{"empty value", "spiffe://cluster/ns/payments/*", "", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := globMatch(tc.pattern, tc.value); got != tc.want {
+				t.Errorf("globMatch(%q, %q) = %v, want %v", tc.pattern, tc.value, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIdentityRuleMatches(t *testing.T) {
+	id := Identity{SPIFFEURI: "spiffe://cluster/ns/payments/api", CommonName: "payments-api"}
+
+	cases := []struct {
+		name string
+		rule IdentityRule
+		want bool
+	}{
+		{"matches spiffe glob", IdentityRule{SPIFFEURIPattern: "spiffe://cluster/ns/payments/*"}, true},
+		{"matches common name glob", IdentityRule{CommonNamePattern: "payments-*"}, true},
+		{
+			"requires every non-empty pattern to match",
+			IdentityRule{SPIFFEURIPattern: "spiffe://cluster/ns/payments/*", CommonNamePattern: "billing-*"},
+			false,
+		},
+		{"rule with no patterns never matches", IdentityRule{}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.rule.Matches(id); got != tc.want {
+				t.Errorf("Matches() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIdentityCheckerCheckDeniesOnMatchingDenyRule(t *testing.T) {
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}}
+	checker := NewIdentityChecker([]IdentityRule{
+		{SPIFFEURIPattern: "spiffe://cluster/ns/payments/*", Allow: false},
+	}, next)
+
+	cert := generateTestCert(t, "payments-api", "spiffe://cluster/ns/payments/api")
+	ctx := contextWithIdentity(cert)
+
+	response, err := checker.Check(ctx, &Request{Context: map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if next.called {
+		t.Error("expected Next not to be called for a denied identity")
+	}
+
+	if response.Response.StatusCode != http.StatusForbidden {
+		t.Errorf("StatusCode = %d, want %d", response.Response.StatusCode, http.StatusForbidden)
+	}
+
+	if got := response.Response.Header.Get("X-Cerberus-Reason"); got != "IdentityDenied" {
+		t.Errorf("X-Cerberus-Reason = %q, want IdentityDenied", got)
+	}
+}
+
+func TestIdentityCheckerCheckAllowsOnMatchingAllowRule(t *testing.T) {
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}}
+	checker := NewIdentityChecker([]IdentityRule{
+		{CommonNamePattern: "payments-*", Allow: true},
+	}, next)
+
+	cert := generateTestCert(t, "payments-api", "")
+	ctx := contextWithIdentity(cert)
+	request := &Request{Context: map[string]interface{}{}}
+
+	response, err := checker.Check(ctx, request)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if !next.called {
+		t.Fatal("expected Next to be called for an allowed identity")
+	}
+
+	if response != next.response {
+		t.Error("expected Next's response to be returned unmodified")
+	}
+
+	if got := request.Context[ClientCommonName]; got != "payments-api" {
+		t.Errorf("request.Context[ClientCommonName] = %v, want payments-api", got)
+	}
+}
+
+func TestIdentityCheckerCheckFallsThroughWithoutPeerIdentity(t *testing.T) {
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}}
+	checker := NewIdentityChecker([]IdentityRule{
+		{SPIFFEURIPattern: "spiffe://cluster/ns/payments/*", Allow: false},
+	}, next)
+
+	_, err := checker.Check(context.Background(), &Request{Context: map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if !next.called {
+		t.Error("expected Next to be called when no verified peer identity is present")
+	}
+}
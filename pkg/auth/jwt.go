@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Context keys populated by JWTChecker on successful validation, read by
+// authV2.Check/authV3.Check to tag the jwt_issuer/jwt_alg metric labels.
+const (
+	JWTIssuerContext  = "jwt_issuer"
+	JWTAlgContext     = "jwt_alg"
+	JWTSubjectContext = "jwt_subject"
+)
+
+// JWTLabel and JWTAlgLabel are the additional prometheus label names emitted
+// for requests authenticated via JWTChecker, alongside the existing reason
+// and upstream-auth labels.
+const (
+	JWTLabel    = "jwt_issuer"
+	JWTAlgLabel = "jwt_alg"
+)
+
+// AddJWTLabels always sets the jwt_issuer/jwt_alg labels on labels, reading
+// the stored values from request when the request was authenticated by a
+// JWTChecker and defaulting to "" otherwise. Like AddReasonLabel and
+// AddUpstreamAuthLabel, it must set its keys unconditionally: reqCount and
+// reqLatency are Prometheus vectors, and With(labels) panics with
+// "inconsistent label cardinality" unless every call supplies the same set
+// of label keys.
+func AddJWTLabels(labels prometheus.Labels, request *Request) prometheus.Labels {
+	if labels == nil {
+		labels = prometheus.Labels{}
+	}
+
+	issuer, _ := request.Context[JWTIssuerContext].(string)
+	alg, _ := request.Context[JWTAlgContext].(string)
+
+	labels[JWTLabel] = issuer
+	labels[JWTAlgLabel] = alg
+
+	return labels
+}
+
+// JWTConfig describes how bearer tokens should be validated for a single
+// webservice.
+type JWTConfig struct {
+	// Issuer is the expected `iss` claim.
+	Issuer string
+	// Audience is the expected `aud` claim.
+	Audience string
+	// JWKSURI, when set, is polled on a background refresh loop (via
+	// keyfunc) to resolve RS256/ES256 signing keys by `kid`.
+	JWKSURI string
+	// StaticKeys resolves a signing key by `kid` without a JWKS endpoint,
+	// for HS256 secrets or pinned RS256/ES256 public keys. The empty key
+	// "" is used as the default when a token carries no `kid`.
+	StaticKeys map[string]interface{}
+	// RefreshInterval controls how often JWKSURI is re-polled. Defaults to
+	// five minutes when zero.
+	RefreshInterval time.Duration
+	// ClaimHeaders maps a claim name to the response header it should be
+	// exposed to upstreams under, e.g. {"sub": "X-Cerberus-Jwt-Subject"}.
+	ClaimHeaders map[string]string
+}
+
+// JWTChecker is a Checker that validates an Authorization bearer token
+// before delegating to Next. Webservices without a matching JWTConfig (keyed
+// by Request.Host) fall straight through to Next unauthenticated by JWT.
+type JWTChecker struct {
+	Configs map[string]JWTConfig
+	Next    Checker
+
+	jwks map[string]*keyfunc.JWKS
+}
+
+// NewJWTChecker builds a JWTChecker from configs, starting a background JWKS
+// refresh loop per webservice with a JWKSURI configured, stopping when ctx is
+// done (mirroring NewOPAChecker's reload-loop lifetime). keyfunc keeps
+// serving the last-known-good key set if a refresh fails, so callers don't
+// need their own fallback handling.
+func NewJWTChecker(ctx context.Context, configs map[string]JWTConfig, next Checker) (*JWTChecker, error) {
+	c := &JWTChecker{
+		Configs: configs,
+		Next:    next,
+		jwks:    map[string]*keyfunc.JWKS{},
+	}
+
+	for name, cfg := range configs {
+		if cfg.JWKSURI == "" {
+			continue
+		}
+
+		interval := cfg.RefreshInterval
+		if interval <= 0 {
+			interval = 5 * time.Minute
+		}
+
+		set, err := keyfunc.Get(cfg.JWKSURI, keyfunc.Options{
+			RefreshInterval:   interval,
+			RefreshUnknownKID: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("auth: fetching JWKS for webservice %q: %w", name, err)
+		}
+
+		c.jwks[name] = set
+	}
+
+	if len(c.jwks) > 0 {
+		go c.stopJWKSOnDone(ctx)
+	}
+
+	return c, nil
+}
+
+// stopJWKSOnDone ends every webservice's background JWKS refresh goroutine
+// once ctx is done, so NewJWTChecker doesn't leak one unbounded
+// goroutine/ticker per call (e.g. on a config reload).
+func (c *JWTChecker) stopJWKSOnDone(ctx context.Context) {
+	<-ctx.Done()
+
+	for _, set := range c.jwks {
+		set.EndBackground()
+	}
+}
+
+// Check implements Checker.
+func (c *JWTChecker) Check(ctx context.Context, request *Request) (*Response, error) {
+	cfg, ok := c.Configs[request.Host]
+	if !ok {
+		return c.Next.Check(ctx, request)
+	}
+
+	token := bearerToken(request.Headers.Get("Authorization"))
+	if token == "" {
+		return jwtDenyResponse("JWTMissing"), nil
+	}
+
+	claims := jwt.MapClaims{}
+
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return c.resolveKey(request.Host, cfg, t)
+	})
+	if err != nil || !parsed.Valid {
+		return jwtDenyResponse("JWTInvalid"), nil
+	}
+
+	// jwt.MapClaims.Valid only verifies exp/nbf when they're present on the
+	// token, so a token that omits exp would otherwise never expire. Require
+	// it explicitly.
+	if _, ok := claims["exp"]; !ok {
+		return jwtDenyResponse("JWTInvalid"), nil
+	}
+
+	if cfg.Issuer != "" && !claims.VerifyIssuer(cfg.Issuer, true) {
+		return jwtDenyResponse("JWTInvalid"), nil
+	}
+
+	if cfg.Audience != "" && !claims.VerifyAudience(cfg.Audience, true) {
+		return jwtDenyResponse("JWTInvalid"), nil
+	}
+
+	if request.Context == nil {
+		request.Context = map[string]interface{}{}
+	}
+
+	request.Context[JWTIssuerContext], _ = claims["iss"].(string)
+	request.Context[JWTAlgContext] = parsed.Method.Alg()
+	request.Context[JWTSubjectContext], _ = claims["sub"].(string)
+
+	response, err := c.Next.Check(ctx, request)
+	if err != nil || response == nil {
+		return response, err
+	}
+
+	if response.Response.Header == nil {
+		response.Response.Header = http.Header{}
+	}
+
+	for claim, header := range cfg.ClaimHeaders {
+		if v, ok := claims[claim]; ok {
+			response.Response.Header.Set(header, fmt.Sprintf("%v", v))
+		}
+	}
+
+	return response, nil
+}
+
+func (c *JWTChecker) resolveKey(webservice string, cfg JWTConfig, t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	if key, ok := cfg.StaticKeys[kid]; ok {
+		return key, nil
+	}
+
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if key, ok := cfg.StaticKeys[""]; ok {
+			return key, nil
+		}
+
+		return nil, fmt.Errorf("no HS256 key configured for webservice %q", webservice)
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		set, ok := c.jwks[webservice]
+		if !ok {
+			return nil, fmt.Errorf("no JWKS configured for webservice %q", webservice)
+		}
+
+		return set.Keyfunc(t)
+	default:
+		return nil, fmt.Errorf("unsupported signing method %v", t.Method.Alg())
+	}
+}
+
+func bearerToken(header string) string {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return ""
+	}
+
+	return strings.TrimPrefix(header, prefix)
+}
+
+func jwtDenyResponse(reason string) *Response {
+	header := http.Header{}
+	header.Set("X-Cerberus-Reason", reason)
+
+	return &Response{Response: &http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Header:     header,
+	}}
+}
@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestBearerToken(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"well formed", "Bearer abc.def.ghi", "abc.def.ghi"},
+		{"missing prefix", "abc.def.ghi", ""},
+		{"empty header", "", ""},
+		{"wrong scheme", "Basic dXNlcjpwYXNz", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := bearerToken(tc.header); got != tc.want {
+				t.Errorf("bearerToken(%q) = %q, want %q", tc.header, got, tc.want)
+			}
+		})
+	}
+}
+
+func signTestJWT(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("signing test JWT: %v", err)
+	}
+
+	return signed
+}
+
+func testJWTChecker(t *testing.T, next Checker) *JWTChecker {
+	t.Helper()
+
+	checker, err := NewJWTChecker(context.Background(), map[string]JWTConfig{
+		"svc.example": {
+			Issuer:       "https://issuer.example",
+			Audience:     "cerberus",
+			StaticKeys:   map[string]interface{}{"": []byte("secret")},
+			ClaimHeaders: map[string]string{"sub": "X-Cerberus-Jwt-Subject"},
+		},
+	}, next)
+	if err != nil {
+		t.Fatalf("NewJWTChecker() error = %v", err)
+	}
+
+	return checker
+}
+
+func TestJWTCheckerCheckAllowsValidTokenAndExposesClaims(t *testing.T) {
+	// Next deliberately returns a nil Header to exercise the guard added
+	// after a prior reported panic.
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK}}}
+	checker := testJWTChecker(t, next)
+
+	token := signTestJWT(t, "secret", jwt.MapClaims{
+		"iss": "https://issuer.example",
+		"aud": "cerberus",
+		"sub": "user-123",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	request := &Request{
+		Host:    "svc.example",
+		Headers: http.Header{"Authorization": []string{"Bearer " + token}},
+		Context: map[string]interface{}{},
+	}
+
+	response, err := checker.Check(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if !next.called {
+		t.Fatal("expected Next to be called for a valid token")
+	}
+
+	if got := response.Response.Header.Get("X-Cerberus-Jwt-Subject"); got != "user-123" {
+		t.Errorf("X-Cerberus-Jwt-Subject = %q, want user-123", got)
+	}
+
+	if got := request.Context[JWTSubjectContext]; got != "user-123" {
+		t.Errorf("request.Context[JWTSubjectContext] = %v, want user-123", got)
+	}
+
+	if got := request.Context[JWTAlgContext]; got != "HS256" {
+		t.Errorf("request.Context[JWTAlgContext] = %v, want HS256", got)
+	}
+}
+
+func TestJWTCheckerCheckDeniesMissingToken(t *testing.T) {
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}}
+	checker := testJWTChecker(t, next)
+
+	request := &Request{Host: "svc.example", Headers: http.Header{}, Context: map[string]interface{}{}}
+
+	response, err := checker.Check(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if next.called {
+		t.Error("expected Next not to be called without a bearer token")
+	}
+
+	if response.Response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("StatusCode = %d, want %d", response.Response.StatusCode, http.StatusUnauthorized)
+	}
+
+	if got := response.Response.Header.Get("X-Cerberus-Reason"); got != "JWTMissing" {
+		t.Errorf("X-Cerberus-Reason = %q, want JWTMissing", got)
+	}
+}
+
+func TestJWTCheckerCheckDeniesInvalidToken(t *testing.T) {
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}}
+	checker := testJWTChecker(t, next)
+
+	request := &Request{
+		Host:    "svc.example",
+		Headers: http.Header{"Authorization": []string{"Bearer not-a-jwt"}},
+		Context: map[string]interface{}{},
+	}
+
+	response, err := checker.Check(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if next.called {
+		t.Error("expected Next not to be called for a malformed token")
+	}
+
+	if got := response.Response.Header.Get("X-Cerberus-Reason"); got != "JWTInvalid" {
+		t.Errorf("X-Cerberus-Reason = %q, want JWTInvalid", got)
+	}
+}
+
+func TestJWTCheckerCheckDeniesExpiredToken(t *testing.T) {
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}}
+	checker := testJWTChecker(t, next)
+
+	token := signTestJWT(t, "secret", jwt.MapClaims{
+		"iss": "https://issuer.example",
+		"aud": "cerberus",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	})
+
+	request := &Request{
+		Host:    "svc.example",
+		Headers: http.Header{"Authorization": []string{"Bearer " + token}},
+		Context: map[string]interface{}{},
+	}
+
+	response, err := checker.Check(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if next.called {
+		t.Error("expected Next not to be called for an expired token")
+	}
+
+	if got := response.Response.Header.Get("X-Cerberus-Reason"); got != "JWTInvalid" {
+		t.Errorf("X-Cerberus-Reason = %q, want JWTInvalid", got)
+	}
+}
+
+func TestJWTCheckerCheckDeniesTokenWithoutExp(t *testing.T) {
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}}
+	checker := testJWTChecker(t, next)
+
+	token := signTestJWT(t, "secret", jwt.MapClaims{
+		"iss": "https://issuer.example",
+		"aud": "cerberus",
+	})
+
+	request := &Request{
+		Host:    "svc.example",
+		Headers: http.Header{"Authorization": []string{"Bearer " + token}},
+		Context: map[string]interface{}{},
+	}
+
+	response, err := checker.Check(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if next.called {
+		t.Error("expected Next not to be called for a token without exp")
+	}
+
+	if got := response.Response.Header.Get("X-Cerberus-Reason"); got != "JWTInvalid" {
+		t.Errorf("X-Cerberus-Reason = %q, want JWTInvalid", got)
+	}
+}
+
+func TestJWTCheckerCheckSkipsUnconfiguredWebservice(t *testing.T) {
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}}
+	checker := testJWTChecker(t, next)
+
+	request := &Request{Host: "other.example", Headers: http.Header{}, Context: map[string]interface{}{}}
+
+	if _, err := checker.Check(context.Background(), request); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if !next.called {
+		t.Error("expected Next to be called directly for a webservice with no JWTConfig")
+	}
+}
@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// defaultOPAQuery is the query Cerberus evaluates against the loaded bundle
+// when OPAConfig.Query is empty.
+const defaultOPAQuery = "data.cerberus.decision"
+
+// ReasonPolicyEvalError is recorded when the Rego policy fails to evaluate;
+// OPAChecker fails closed (denies) in this case.
+const ReasonPolicyEvalError CerberusReason = "PolicyEvalError"
+
+// OPAConfig configures an OPAChecker's policy source.
+type OPAConfig struct {
+	// Path loads the Rego policy from a single local file; a directory is
+	// not read. Mutually exclusive with URL.
+	Path string
+	// URL loads the Rego policy from an HTTP endpoint, re-polled on
+	// PollInterval using conditional GETs (If-None-Match) so an unchanged
+	// policy is a cheap 304. The response body is compiled as one Rego
+	// module; OPA bundle archives are not unpacked. Mutually exclusive with
+	// Path.
+	URL string
+	// PollInterval controls how often Path/URL is checked for changes.
+	// Defaults to thirty seconds when zero.
+	PollInterval time.Duration
+	// Query is the Rego query evaluated per request. Defaults to
+	// defaultOPAQuery when empty, expected to yield an object with
+	// `allowed`, and optionally `headers`, `status`, and `reason` keys.
+	Query string
+}
+
+// opaDecision is the shape OPAChecker expects back from evaluating
+// OPAConfig.Query, mapping directly onto response.Response.
+type opaDecision struct {
+	Allowed bool              `json:"allowed"`
+	Headers map[string]string `json:"headers"`
+	Status  int               `json:"status"`
+	Reason  string            `json:"reason"`
+}
+
+// OPAChecker is a Checker that evaluates a hot-reloadable Rego policy bundle
+// for each request, so RegisterServer(srv, c) can be wired to policy-as-code
+// instead of (or in addition to) static rule matching.
+type OPAChecker struct {
+	cfg OPAConfig
+
+	client *http.Client
+	etag   string
+
+	mu    sync.RWMutex
+	query rego.PreparedEvalQuery
+}
+
+// NewOPAChecker loads cfg's policy and starts a background reload loop,
+// stopping when ctx is done.
+func NewOPAChecker(ctx context.Context, cfg OPAConfig) (*OPAChecker, error) {
+	if cfg.Query == "" {
+		cfg.Query = defaultOPAQuery
+	}
+
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 30 * time.Second
+	}
+
+	c := &OPAChecker{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if err := c.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	go c.reloadLoop(ctx)
+
+	return c, nil
+}
+
+func (c *OPAChecker) reloadLoop(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A reload failure leaves the previous PreparedEvalQuery in
+			// place; the next tick tries again.
+			_ = c.reload(ctx)
+		}
+	}
+}
+
+func (c *OPAChecker) reload(ctx context.Context) error {
+	module, changed, err := c.fetchModule()
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		return nil
+	}
+
+	query, err := rego.New(
+		rego.Query(c.cfg.Query),
+		rego.Module("cerberus.rego", module),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return fmt.Errorf("auth: compiling OPA policy: %w", err)
+	}
+
+	c.mu.Lock()
+	c.query = query
+	c.mu.Unlock()
+
+	return nil
+}
+
+// fetchModule returns the current policy source text and whether it should
+// be recompiled. A Path policy is re-read and reported as changed on every
+// call. A URL policy counts as unchanged only on a 304 response to the
+// If-None-Match etag of the previous fetch; the zero-value etag on first call
+// always counts as changed.
+func (c *OPAChecker) fetchModule() (module string, changed bool, err error) {
+	if c.cfg.Path != "" {
+		data, err := os.ReadFile(c.cfg.Path)
+		if err != nil {
+			return "", false, fmt.Errorf("auth: reading OPA policy: %w", err)
+		}
+
+		return string(data), true, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.cfg.URL, nil)
+	if err != nil {
+		return "", false, fmt.Errorf("auth: building OPA bundle request: %w", err)
+	}
+
+	if c.etag != "" {
+		req.Header.Set("If-None-Match", c.etag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("auth: fetching OPA bundle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return "", false, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("auth: fetching OPA bundle: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", false, fmt.Errorf("auth: reading OPA bundle: %w", err)
+	}
+
+	c.etag = resp.Header.Get("ETag")
+
+	return string(data), true, nil
+}
+
+// Check implements Checker.
+func (c *OPAChecker) Check(ctx context.Context, request *Request) (*Response, error) {
+	c.mu.RLock()
+	query := c.query
+	c.mu.RUnlock()
+
+	results, err := query.Eval(ctx, rego.EvalInput(opaInput(request)))
+	if err != nil || len(results) == 0 || len(results[0].Expressions) == 0 {
+		return opaDenyResponse(ReasonPolicyEvalError), nil
+	}
+
+	decision, ok := decodeOPADecision(results[0].Expressions[0].Value)
+	if !ok {
+		return opaDenyResponse(ReasonPolicyEvalError), nil
+	}
+
+	header := http.Header{}
+	for k, v := range decision.Headers {
+		header.Set(k, v)
+	}
+
+	if decision.Reason != "" {
+		header.Set("X-Cerberus-Reason", decision.Reason)
+	}
+
+	status := decision.Status
+	if status == 0 {
+		if decision.Allowed {
+			status = http.StatusOK
+		} else {
+			status = http.StatusForbidden
+		}
+	}
+
+	return &Response{Response: &http.Response{StatusCode: status, Header: header}}, nil
+}
+
+func opaInput(request *Request) map[string]interface{} {
+	headers := map[string]interface{}{}
+	for k := range request.Headers {
+		headers[k] = request.Headers.Get(k)
+	}
+
+	return map[string]interface{}{
+		"method":  request.Method,
+		"path":    request.Path,
+		"host":    request.Host,
+		"headers": headers,
+		"context": request.Context,
+	}
+}
+
+func decodeOPADecision(value interface{}) (opaDecision, bool) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return opaDecision{}, false
+	}
+
+	decision := opaDecision{Headers: map[string]string{}}
+
+	decision.Allowed, _ = obj["allowed"].(bool)
+	decision.Reason, _ = obj["reason"].(string)
+
+	switch status := obj["status"].(type) {
+	case json.Number:
+		if n, err := status.Int64(); err == nil {
+			decision.Status = int(n)
+		}
+	case float64:
+		decision.Status = int(status)
+	}
+
+	if headers, ok := obj["headers"].(map[string]interface{}); ok {
+		for k, v := range headers {
+			if s, ok := v.(string); ok {
+				decision.Headers[k] = s
+			}
+		}
+	}
+
+	return decision, true
+}
+
+func opaDenyResponse(reason CerberusReason) *Response {
+	header := http.Header{}
+	header.Set("X-Cerberus-Reason", string(reason))
+
+	return &Response{Response: &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     header,
+	}}
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeOPADecision(t *testing.T) {
+	cases := []struct {
+		name  string
+		value interface{}
+		ok    bool
+		want  opaDecision
+	}{
+		{
+			name: "allowed with headers",
+			value: map[string]interface{}{
+				"allowed": true,
+				"status":  float64(200),
+				"headers": map[string]interface{}{"X-User": "alice"},
+			},
+			ok:   true,
+			want: opaDecision{Allowed: true, Status: 200, Headers: map[string]string{"X-User": "alice"}},
+		},
+		{
+			name: "denied with reason",
+			value: map[string]interface{}{
+				"allowed": false,
+				"reason":  "NotEntitled",
+			},
+			ok:   true,
+			want: opaDecision{Allowed: false, Reason: "NotEntitled", Headers: map[string]string{}},
+		},
+		{
+			name:  "non-object result fails to decode",
+			value: "not-an-object",
+			ok:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := decodeOPADecision(tc.value)
+			if ok != tc.ok {
+				t.Fatalf("ok = %v, want %v", ok, tc.ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			if got.Allowed != tc.want.Allowed || got.Status != tc.want.Status || got.Reason != tc.want.Reason {
+				t.Errorf("decodeOPADecision() = %+v, want %+v", got, tc.want)
+			}
+
+			for k, v := range tc.want.Headers {
+				if got.Headers[k] != v {
+					t.Errorf("Headers[%q] = %q, want %q", k, got.Headers[k], v)
+				}
+			}
+		})
+	}
+}
+
+const testOPAPolicy = `package cerberus
+
+default decision = {"allowed": false, "status": 403, "reason": "Forbidden"}
+
+decision = {"allowed": true, "status": 200} {
+	input.headers["X-Api-Key"] == "secret"
+}
+`
+
+func newTestOPAChecker(t *testing.T) *OPAChecker {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "policy.rego")
+	if err := os.WriteFile(path, []byte(testOPAPolicy), 0o600); err != nil {
+		t.Fatalf("writing policy: %v", err)
+	}
+
+	checker, err := NewOPAChecker(context.Background(), OPAConfig{Path: path})
+	if err != nil {
+		t.Fatalf("NewOPAChecker() error = %v", err)
+	}
+
+	return checker
+}
+
+func TestOPACheckerCheckAllowsOnMatchingPolicy(t *testing.T) {
+	checker := newTestOPAChecker(t)
+
+	request := &Request{Headers: http.Header{"X-Api-Key": []string{"secret"}}}
+
+	response, err := checker.Check(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if response.Response.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want %d", response.Response.StatusCode, http.StatusOK)
+	}
+}
+
+func TestOPACheckerCheckDeniesOnDefaultPolicy(t *testing.T) {
+	checker := newTestOPAChecker(t)
+
+	request := &Request{Headers: http.Header{}}
+
+	response, err := checker.Check(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	if response.Response.StatusCode != http.StatusForbidden {
+		t.Errorf("StatusCode = %d, want %d", response.Response.StatusCode, http.StatusForbidden)
+	}
+
+	if got := response.Response.Header.Get("X-Cerberus-Reason"); got != "Forbidden" {
+		t.Errorf("X-Cerberus-Reason = %q, want Forbidden", got)
+	}
+}
@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+// ReasonRateLimited is recorded when RateLimitChecker rejects a request.
+const ReasonRateLimited CerberusReason = "RateLimited"
+
+// RateLimitConfig bounds how much traffic a single key (API key, JWT
+// subject, or mTLS SPIFFE id) may send to a webservice.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the sustained token-bucket refill rate.
+	RequestsPerSecond float64
+	// Burst is the token-bucket capacity. Defaults to the integer part of
+	// RequestsPerSecond plus one when zero.
+	Burst int
+	// MaxConcurrent caps in-flight requests for the key. Zero means
+	// unlimited.
+	MaxConcurrent int
+}
+
+// RateLimiterConfig configures a RateLimitChecker.
+type RateLimiterConfig struct {
+	// Default applies to webservices with no entry in Overrides.
+	Default RateLimitConfig
+	// Overrides holds per-webservice limits, keyed by Request.Host.
+	Overrides map[string]RateLimitConfig
+	// Redis, when set, makes the rate (but not the concurrency cap) shared
+	// across Cerberus replicas. A bucket falls back to the in-memory
+	// limiter for the duration of any Redis error.
+	Redis *redis.Client
+}
+
+// RateLimitChecker is a Checker that enforces RateLimiterConfig before
+// delegating to Next.
+type RateLimitChecker struct {
+	cfg  RateLimiterConfig
+	Next Checker
+
+	mu       sync.Mutex
+	buckets  map[string]*rate.Limiter
+	inflight map[string]chan struct{}
+}
+
+// NewRateLimitChecker returns a RateLimitChecker enforcing cfg in front of
+// next.
+func NewRateLimitChecker(cfg RateLimiterConfig, next Checker) *RateLimitChecker {
+	return &RateLimitChecker{
+		cfg:      cfg,
+		Next:     next,
+		buckets:  map[string]*rate.Limiter{},
+		inflight: map[string]chan struct{}{},
+	}
+}
+
+// Check implements Checker.
+func (c *RateLimitChecker) Check(ctx context.Context, request *Request) (*Response, error) {
+	limit := c.configFor(request.Host)
+	key := bucketKey(request.Host, rateLimitKey(request))
+
+	release, ok := c.acquireConcurrency(key, limit)
+	if !ok {
+		return rateLimitedResponse(limit), nil
+	}
+	defer release()
+
+	allowed, err := c.allow(ctx, key, limit)
+	if err != nil {
+		// allow answers Redis errors from the in-memory bucket and so returns
+		// a nil error; any non-nil error fails open: availability over strict
+		// enforcement.
+		allowed = true
+	}
+
+	if !allowed {
+		return rateLimitedResponse(limit), nil
+	}
+
+	return c.Next.Check(ctx, request)
+}
+
+func (c *RateLimitChecker) configFor(webservice string) RateLimitConfig {
+	if cfg, ok := c.cfg.Overrides[webservice]; ok {
+		return cfg
+	}
+
+	return c.cfg.Default
+}
+
+func (c *RateLimitChecker) acquireConcurrency(key string, limit RateLimitConfig) (release func(), ok bool) {
+	if limit.MaxConcurrent <= 0 {
+		return func() {}, true
+	}
+
+	c.mu.Lock()
+	sem, exists := c.inflight[key]
+	if !exists {
+		sem = make(chan struct{}, limit.MaxConcurrent)
+		c.inflight[key] = sem
+	}
+	c.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, true
+	default:
+		return nil, false
+	}
+}
+
+func (c *RateLimitChecker) allow(ctx context.Context, key string, limit RateLimitConfig) (bool, error) {
+	if c.cfg.Redis != nil {
+		allowed, err := c.allowRedis(ctx, key, limit)
+		if err == nil {
+			return allowed, nil
+		}
+		// Redis unavailable: fall back to the in-memory bucket below.
+	}
+
+	return c.allowLocal(key, limit), nil
+}
+
+func (c *RateLimitChecker) allowLocal(key string, limit RateLimitConfig) bool {
+	c.mu.Lock()
+	bucket, ok := c.buckets[key]
+	if !ok {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = int(limit.RequestsPerSecond) + 1
+		}
+
+		bucket = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+		c.buckets[key] = bucket
+	}
+	c.mu.Unlock()
+
+	return bucket.Allow()
+}
+
+// allowRedis enforces a per-second request cap shared across replicas using
+// an INCR-and-expire counter keyed by the current second. The cap for each
+// one-second window is the bucket's Burst (with the same default as the
+// local bucket), not RequestsPerSecond.
+func (c *RateLimitChecker) allowRedis(ctx context.Context, key string, limit RateLimitConfig) (bool, error) {
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = int(limit.RequestsPerSecond) + 1
+	}
+
+	redisKey := fmt.Sprintf("cerberus:ratelimit:%s:%d", key, time.Now().Unix())
+
+	count, err := c.cfg.Redis.Incr(ctx, redisKey).Result()
+	if err != nil {
+		return false, err
+	}
+
+	if count == 1 {
+		c.cfg.Redis.Expire(ctx, redisKey, time.Second)
+	}
+
+	return int(count) <= burst, nil
+}
+
+// bucketKey scopes a caller's rate-limit state to the webservice being
+// called, so a per-webservice Overrides entry can't leak a caller's bucket
+// or Redis counter from one webservice's limit into another's.
+func bucketKey(webservice, identity string) string {
+	return webservice + "|" + identity
+}
+
+func rateLimitKey(request *Request) string {
+	if key := request.Headers.Get("X-Api-Key"); key != "" {
+		return key
+	}
+
+	if sub, ok := request.Context[JWTSubjectContext].(string); ok && sub != "" {
+		return sub
+	}
+
+	if spiffe, ok := request.Context[ClientSPIFFEURI].(string); ok && spiffe != "" {
+		return spiffe
+	}
+
+	return "anonymous"
+}
+
+func rateLimitedResponse(limit RateLimitConfig) *Response {
+	retryAfter := 1
+	if limit.RequestsPerSecond > 0 && limit.RequestsPerSecond < 1 {
+		retryAfter = int(1 / limit.RequestsPerSecond)
+	}
+
+	header := http.Header{}
+	header.Set("X-Cerberus-Reason", string(ReasonRateLimited))
+	header.Set("Retry-After", strconv.Itoa(retryAfter))
+
+	return &Response{Response: &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     header,
+	}}
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestRateLimitCheckerAllowLocalEnforcesBurst(t *testing.T) {
+	c := NewRateLimitChecker(RateLimiterConfig{}, nil)
+	limit := RateLimitConfig{RequestsPerSecond: 1, Burst: 2}
+
+	if !c.allowLocal("key", limit) {
+		t.Fatal("expected first request within burst to be allowed")
+	}
+
+	if !c.allowLocal("key", limit) {
+		t.Fatal("expected second request within burst to be allowed")
+	}
+
+	if c.allowLocal("key", limit) {
+		t.Fatal("expected third request to exceed burst and be denied")
+	}
+}
+
+func TestRateLimitCheckerAllowLocalPerKey(t *testing.T) {
+	c := NewRateLimitChecker(RateLimiterConfig{}, nil)
+	limit := RateLimitConfig{RequestsPerSecond: 1, Burst: 1}
+
+	if !c.allowLocal("a", limit) {
+		t.Fatal("expected key a's first request to be allowed")
+	}
+
+	if !c.allowLocal("b", limit) {
+		t.Fatal("expected independent key b to have its own bucket")
+	}
+}
+
+func TestRateLimitCheckerCheckAppliesOverridesPerWebservice(t *testing.T) {
+	next := &fakeChecker{response: &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}}}
+	checker := NewRateLimitChecker(RateLimiterConfig{
+		Default: RateLimitConfig{RequestsPerSecond: 100, Burst: 100},
+		Overrides: map[string]RateLimitConfig{
+			"strict.example":  {RequestsPerSecond: 1, Burst: 1},
+			"lenient.example": {RequestsPerSecond: 100, Burst: 100},
+		},
+	}, next)
+
+	// Both webservices share the same caller key (API key); only
+	// strict.example's budget should ever be exhausted by it.
+	strictReq := &Request{Host: "strict.example", Headers: http.Header{"X-Api-Key": []string{"shared-key"}}, Context: map[string]interface{}{}}
+	lenientReq := &Request{Host: "lenient.example", Headers: http.Header{"X-Api-Key": []string{"shared-key"}}, Context: map[string]interface{}{}}
+
+	if _, err := checker.Check(context.Background(), strictReq); err != nil {
+		t.Fatalf("first strict.example Check() error = %v", err)
+	}
+
+	response, err := checker.Check(context.Background(), strictReq)
+	if err != nil {
+		t.Fatalf("second strict.example Check() error = %v", err)
+	}
+
+	if response.Response.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("second strict.example request StatusCode = %d, want %d (burst of 1 exhausted)",
+			response.Response.StatusCode, http.StatusTooManyRequests)
+	}
+
+	response, err = checker.Check(context.Background(), lenientReq)
+	if err != nil {
+		t.Fatalf("lenient.example Check() error = %v", err)
+	}
+
+	if response.Response.StatusCode != http.StatusOK {
+		t.Errorf("lenient.example request StatusCode = %d, want %d (must not share strict.example's exhausted bucket)",
+			response.Response.StatusCode, http.StatusOK)
+	}
+}
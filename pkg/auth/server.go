@@ -37,7 +37,10 @@ func (a *authV2) Check(ctx context.Context, check *CheckRequestV2) (*CheckRespon
 	request := Request{}
 	request.FromV2(check)
 
+	ctx, span := startCheckSpan(ctx, &request, MetricsCheckRequestVersion2)
+
 	response, err := a.Checker.Check(ctx, &request)
+	finishCheckSpan(span, response, &request, err)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +50,7 @@ func (a *authV2) Check(ctx context.Context, check *CheckRequestV2) (*CheckRespon
 	reason := CerberusReason(response.Response.Header.Get("X-Cerberus-Reason"))
 	labels := AddReasonLabel(nil, reason)
 	labels = AddUpstreamAuthLabel(labels, request.Context[HasUpstreamAuth])
+	labels = AddJWTLabels(labels, &request)
 	labels[CheckRequestVersionLabel] = MetricsCheckRequestVersion2
 	reqCount.With(labels).Inc()
 	reqLatency.With(labels).Observe(time.Since(reqStartTime).Seconds())
@@ -63,7 +67,10 @@ func (a *authV3) Check(ctx context.Context, check *CheckRequestV3) (*CheckRespon
 	request := Request{}
 	request.FromV3(check)
 
+	ctx, span := startCheckSpan(ctx, &request, MetricsCheckRequestVersion3)
+
 	response, err := a.Checker.Check(ctx, &request)
+	finishCheckSpan(span, response, &request, err)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +80,7 @@ func (a *authV3) Check(ctx context.Context, check *CheckRequestV3) (*CheckRespon
 	reason := CerberusReason(response.Response.Header.Get("X-Cerberus-Reason"))
 	labels := AddReasonLabel(nil, reason)
 	labels = AddUpstreamAuthLabel(labels, request.Context[HasUpstreamAuth])
+	labels = AddJWTLabels(labels, &request)
 	labels[CheckRequestVersionLabel] = MetricsCheckRequestVersion3
 	reqCount.With(labels).Inc()
 	reqLatency.With(labels).Observe(time.Since(reqStartTime).Seconds())
@@ -81,7 +89,8 @@ func (a *authV3) Check(ctx context.Context, check *CheckRequestV3) (*CheckRespon
 }
 
 // RegisterServer registers the Checker with the external authorization
-// GRPC server.
+// GRPC server. Pair srv with grpc.NewServer(StatsHandler()) so gRPC-level
+// spans are recorded alongside the per-request "cerberus.Check" spans.
 func RegisterServer(srv *grpc.Server, c Checker) {
 	v2 := &authV2{Checker: c}
 	v3 := &authV3{Checker: c}
@@ -108,13 +117,20 @@ func RunServer(ctx context.Context, listener net.Listener, srv *grpc.Server) err
 }
 
 // NewServerCredentials loads TLS transport credentials for the GRPC server.
+//
+// When caPath is non-empty, the server additionally requires and verifies a
+// client certificate signed by that CA, enabling Cerberus to run with mutual
+// TLS in front of (or alongside) an Envoy ext_authz deployment.
 func NewServerCredentials(certPath string, keyPath string, caPath string) (credentials.TransportCredentials, error) {
 	srv, err := tls.LoadX509KeyPair(certPath, keyPath)
 	if err != nil {
 		return nil, err
 	}
 
-	p := x509.NewCertPool()
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{srv},
+	}
 
 	if caPath != "" {
 		ca, err := os.ReadFile(caPath) //nolint:gosec
@@ -122,12 +138,12 @@ func NewServerCredentials(certPath string, keyPath string, caPath string) (crede
 			return nil, err
 		}
 
+		p := x509.NewCertPool()
 		p.AppendCertsFromPEM(ca)
+
+		cfg.ClientCAs = p
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 
-	return credentials.NewTLS(&tls.Config{
-		MinVersion:   tls.VersionTLS12,
-		Certificates: []tls.Certificate{srv},
-		RootCAs:      p,
-	}), nil
+	return credentials.NewTLS(cfg), nil
 }
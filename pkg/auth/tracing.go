@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// tracerName identifies the Cerberus tracer in the OpenTelemetry pipeline.
+const tracerName = "cerberus"
+
+var tracer = otel.Tracer(tracerName)
+
+// TracingConfig configures the OTLP exporter used to report Cerberus spans.
+type TracingConfig struct {
+	// OTLPEndpoint is the gRPC OTLP collector endpoint, e.g.
+	// "otel-collector:4317". Tracing is disabled when empty.
+	OTLPEndpoint string
+	// SampleRatio is the fraction of traces recorded, in [0, 1]. Defaults to
+	// 1 (always sample) when zero. It is ignored for traces that already
+	// carry a sampling decision from Envoy/upstream.
+	SampleRatio float64
+	// Insecure disables TLS on the OTLP exporter connection.
+	Insecure bool
+}
+
+// InitTracer installs a global OpenTelemetry tracer provider exporting spans
+// to cfg.OTLPEndpoint over OTLP/gRPC, so Cerberus spans join the Envoy trace
+// rather than appearing as orphans. It returns a shutdown func to flush and
+// close the exporter, and a no-op shutdown func when cfg.OTLPEndpoint is
+// empty.
+func InitTracer(ctx context.Context, cfg TracingConfig) (func(context.Context) error, error) {
+	if cfg.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("auth: creating OTLP exporter: %w", err)
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio == 0 {
+		ratio = 1
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(
+		semconv.ServiceNameKey.String(tracerName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("auth: building tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+// StatsHandler returns a grpc.ServerOption installing the OpenTelemetry
+// StatsHandler, for use alongside RegisterServer when constructing the
+// *grpc.Server so gRPC-level spans are recorded too.
+func StatsHandler() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler())
+}
+
+// startCheckSpan extracts the W3C traceparent/tracestate that Envoy forwards
+// as ordinary headers on the ext_authz CheckRequest, and starts a child span
+// named "cerberus.Check" from it so the Check call joins the Envoy trace.
+func startCheckSpan(ctx context.Context, request *Request, version string) (context.Context, trace.Span) {
+	carrier := propagation.HeaderCarrier(request.Headers)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
+
+	ctx, span := tracer.Start(ctx, "cerberus.Check", trace.WithAttributes(
+		attribute.String("cerberus.version", version),
+		attribute.String("http.path", request.Path),
+		attribute.String("cerberus.webservice", request.Host),
+	))
+
+	return ctx, span
+}
+
+// finishCheckSpan records the outcome of a Check call on span and ends it.
+func finishCheckSpan(span trace.Span, response *Response, request *Request, err error) {
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+
+	if response != nil {
+		span.SetAttributes(
+			attribute.String("cerberus.reason", response.Response.Header.Get("X-Cerberus-Reason")),
+		)
+	}
+
+	// Read the stored value itself, the same way AddUpstreamAuthLabel does
+	// for the reqCount/reqLatency labels, rather than treating key presence
+	// as true: HasUpstreamAuth can be explicitly set to false.
+	hasUpstreamAuth, _ := request.Context[HasUpstreamAuth].(bool)
+	span.SetAttributes(attribute.Bool("cerberus.has_upstream_auth", hasUpstreamAuth))
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordCheckSpan(t *testing.T, request *Request, response *Response, err error) tracetest.SpanStub {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, span := provider.Tracer("test").Start(context.Background(), "cerberus.Check")
+	finishCheckSpan(span, response, request, err)
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 ended span, got %d", len(ended))
+	}
+
+	return tracetest.SpanStubFromReadOnlySpan(ended[0])
+}
+
+func boolAttribute(t *testing.T, span tracetest.SpanStub, key string) bool {
+	t.Helper()
+
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.AsBool()
+		}
+	}
+
+	t.Fatalf("attribute %q not found on span", key)
+	return false
+}
+
+func stringAttribute(t *testing.T, span tracetest.SpanStub, key string) string {
+	t.Helper()
+
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.AsString()
+		}
+	}
+
+	t.Fatalf("attribute %q not found on span", key)
+	return ""
+}
+
+func TestFinishCheckSpanUsesStoredUpstreamAuthValue(t *testing.T) {
+	response := &Response{Response: &http.Response{Header: http.Header{}}}
+
+	cases := []struct {
+		name    string
+		context map[string]interface{}
+		want    bool
+	}{
+		{"explicitly false is not reported as true", map[string]interface{}{HasUpstreamAuth: false}, false},
+		{"explicitly true is reported as true", map[string]interface{}{HasUpstreamAuth: true}, true},
+		{"absent key defaults to false", map[string]interface{}{}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := &Request{Context: tc.context}
+
+			span := recordCheckSpan(t, request, response, nil)
+
+			if got := boolAttribute(t, span, "cerberus.has_upstream_auth"); got != tc.want {
+				t.Errorf("cerberus.has_upstream_auth = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFinishCheckSpanRecordsReason(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Cerberus-Reason", "IdentityDenied")
+	response := &Response{Response: &http.Response{Header: header}}
+	request := &Request{Context: map[string]interface{}{}}
+
+	span := recordCheckSpan(t, request, response, nil)
+
+	if got := stringAttribute(t, span, "cerberus.reason"); got != "IdentityDenied" {
+		t.Errorf("cerberus.reason = %q, want IdentityDenied", got)
+	}
+}